@@ -2,6 +2,8 @@
 package onetimesecret
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -15,6 +17,85 @@ const baseAPI = "https://onetimesecret.com/api/v1"
 // Client is how we interact with onetimesecret.com
 type Client struct {
 	Username, APIToken string
+
+	// BaseURL overrides the onetimesecret.com API endpoint, for talking
+	// to a self-hosted OTS instance. Defaults to baseAPI.
+	BaseURL string
+
+	// HTTPClient is used to perform requests. Defaults to
+	// http.DefaultClient. Set this to inject a custom Transport for
+	// proxies, logging, or retry middleware.
+	HTTPClient *http.Client
+
+	// UserAgent is sent as the User-Agent header on every request.
+	// Defaults to "go-onetimesecret/0.1".
+	UserAgent string
+
+	// RequestTimeout caps how long a single request is allowed to take.
+	// Zero means no timeout.
+	RequestTimeout time.Duration
+
+	// TLSConfig is installed on the underlying http.Transport when
+	// HTTPClient is not set, so a self-hosted OTS instance behind an
+	// mTLS-terminating reverse proxy can be authenticated to with a
+	// client certificate instead of (or in addition to) HTTP Basic
+	// auth. Use LoadClientTLSConfig to build one from cert/key files.
+	TLSConfig *tls.Config
+
+	// tlsClient caches the *http.Client built from TLSConfig, so repeated
+	// calls (e.g. from SecretHandle) reuse the same Transport instead of
+	// dialing a fresh TLS connection per request.
+	tlsClient *http.Client
+}
+
+// baseURL returns c.BaseURL, falling back to the default onetimesecret.com
+// API endpoint.
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return baseAPI
+}
+
+// shareURL builds the link to give a recipient for secretKey, derived from
+// c.baseURL() so a self-hosted instance gets links back to itself instead of
+// the public onetimesecret.com.
+func (c *Client) shareURL(secretKey string) string {
+	base := strings.TrimSuffix(c.baseURL(), "/api/v1")
+	return base + "/secret/" + secretKey
+}
+
+// ShareURL returns the link to give a recipient for secretKey, derived from
+// c.BaseURL so a self-hosted instance gets links back to itself instead of
+// the public onetimesecret.com. Callers outside this package (such as the
+// CLI) should use this instead of hardcoding the onetimesecret.com URL.
+func (c *Client) ShareURL(secretKey string) string {
+	return c.shareURL(secretKey)
+}
+
+// httpClient returns c.HTTPClient, falling back to http.DefaultClient. If
+// c.TLSConfig is set and c.HTTPClient is not, a client using it is built on
+// first use and cached in c.tlsClient, so repeated calls reuse the same
+// Transport (and its connection pool) instead of dialing fresh each time.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	if c.TLSConfig != nil {
+		if c.tlsClient == nil {
+			c.tlsClient = &http.Client{Transport: &http.Transport{TLSClientConfig: c.TLSConfig}}
+		}
+		return c.tlsClient
+	}
+	return http.DefaultClient
+}
+
+// userAgent returns c.UserAgent, falling back to the default.
+func (c *Client) userAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+	return "go-onetimesecret/0.1"
 }
 
 // Option defines specific optional features of managing secrets
@@ -48,26 +129,41 @@ func WithRecipient(toEmail string) Option {
 	}
 }
 
-// CreateSecret creates a secret with value and returns the metadata
+// CreateSecret creates a secret with value and returns the metadata. It is
+// equivalent to CreateSecretContext(context.Background(), value, opts...)
 func (c *Client) CreateSecret(value string, opts ...Option) (Metadata, error) {
+	return c.CreateSecretContext(context.Background(), value, opts...)
+}
+
+// CreateSecretContext is CreateSecret with a caller-supplied context, for
+// enforcing a deadline or cancelling the request.
+func (c *Client) CreateSecretContext(ctx context.Context, value string, opts ...Option) (Metadata, error) {
 	m := Metadata{}
 	data := url.Values{
 		"secret": {value},
 	}
 	apply(data, opts...)
-	err := c.Do("POST", "/share", data, &m)
+	err := c.DoContext(ctx, "POST", "/share", data, &m)
 	if err != nil {
 		return Metadata{}, err
 	}
 	return m, nil
 }
 
-// GenerateSecret creates a secret in onetimesecret and gives you the metadata to share with consumers.
+// GenerateSecret creates a secret in onetimesecret and gives you the metadata
+// to share with consumers. It is equivalent to
+// GenerateSecretContext(context.Background(), opts...)
 func (c *Client) GenerateSecret(opts ...Option) (GeneratedSecret, error) {
+	return c.GenerateSecretContext(context.Background(), opts...)
+}
+
+// GenerateSecretContext is GenerateSecret with a caller-supplied context, for
+// enforcing a deadline or cancelling the request.
+func (c *Client) GenerateSecretContext(ctx context.Context, opts ...Option) (GeneratedSecret, error) {
 	s := GeneratedSecret{}
 	data := url.Values{}
 	apply(data, opts...)
-	err := c.Do("POST", "/generate", data, &s)
+	err := c.DoContext(ctx, "POST", "/generate", data, &s)
 	if err != nil {
 		return GeneratedSecret{}, err
 	}
@@ -79,15 +175,29 @@ func (c *Client) RetrieveSecret(secretKey string) (secretValue string, err error
 	return c.RetrieveSecretWithPassphrase(secretKey, "")
 }
 
+// RetrieveSecretContext is RetrieveSecret with a caller-supplied context, for
+// enforcing a deadline or cancelling the request.
+func (c *Client) RetrieveSecretContext(ctx context.Context, secretKey string) (secretValue string, err error) {
+	return c.RetrieveSecretWithPassphraseContext(ctx, secretKey, "")
+}
+
 // RetrieveSecretWithPassphrase fetches the secret value from that is encrypted with a password.
-// If passphrase is the empty string it is ignored.
+// If passphrase is the empty string it is ignored. It is equivalent to
+// RetrieveSecretWithPassphraseContext(context.Background(), secretKey, passphrase)
 func (c *Client) RetrieveSecretWithPassphrase(secretKey, passphrase string) (secretValue string, err error) {
+	return c.RetrieveSecretWithPassphraseContext(context.Background(), secretKey, passphrase)
+}
+
+// RetrieveSecretWithPassphraseContext is RetrieveSecretWithPassphrase with a
+// caller-supplied context, for enforcing a deadline or cancelling the
+// request.
+func (c *Client) RetrieveSecretWithPassphraseContext(ctx context.Context, secretKey, passphrase string) (secretValue string, err error) {
 	data := url.Values{}
 	s := GeneratedSecret{}
 	if passphrase != "" {
 		data.Set("passphrase", passphrase)
 	}
-	err = c.Do("POST", "/secret/"+secretKey, data, &s)
+	err = c.DoContext(ctx, "POST", "/secret/"+secretKey, data, &s)
 	if err != nil {
 		return "", err
 	}
@@ -95,10 +205,35 @@ func (c *Client) RetrieveSecretWithPassphrase(secretKey, passphrase string) (sec
 
 }
 
-// RetrieveMetadata gets metadata about secret
+// BurnSecret destroys a secret before it has been read, identified by its
+// metadata key, and returns the metadata describing it. It is equivalent to
+// BurnSecretContext(context.Background(), metadataKey)
+func (c *Client) BurnSecret(metadataKey string) (Metadata, error) {
+	return c.BurnSecretContext(context.Background(), metadataKey)
+}
+
+// BurnSecretContext is BurnSecret with a caller-supplied context, for
+// enforcing a deadline or cancelling the request.
+func (c *Client) BurnSecretContext(ctx context.Context, metadataKey string) (Metadata, error) {
+	m := Metadata{}
+	err := c.DoContext(ctx, "POST", "/private/"+metadataKey+"/burn", nil, &m)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return m, nil
+}
+
+// RetrieveMetadata gets metadata about secret. It is equivalent to
+// RetrieveMetadataContext(context.Background(), metadataKey)
 func (c *Client) RetrieveMetadata(metadataKey string) (Metadata, error) {
+	return c.RetrieveMetadataContext(context.Background(), metadataKey)
+}
+
+// RetrieveMetadataContext is RetrieveMetadata with a caller-supplied context,
+// for enforcing a deadline or cancelling the request.
+func (c *Client) RetrieveMetadataContext(ctx context.Context, metadataKey string) (Metadata, error) {
 	m := Metadata{}
-	err := c.Do("POST", "/private/"+metadataKey, nil, &m)
+	err := c.DoContext(ctx, "POST", "/private/"+metadataKey, nil, &m)
 	if err != nil {
 		return Metadata{}, err
 	}
@@ -123,9 +258,23 @@ var (
 	respCB func(resp *http.Response)
 )
 
-// Do performs the actual API interaction
+// Do performs the actual API interaction. It is equivalent to
+// DoContext(context.Background(), method, path, params, out)
 func (c *Client) Do(method, path string, params url.Values, out interface{}) error {
-	req, err := http.NewRequest(method, baseAPI+path, strings.NewReader(params.Encode()))
+	return c.DoContext(context.Background(), method, path, params, out)
+}
+
+// DoContext is Do with a caller-supplied context, for enforcing a deadline
+// or cancelling the request. RequestTimeout, if set, still applies on top of
+// ctx.
+func (c *Client) DoContext(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	if c.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL()+path, strings.NewReader(params.Encode()))
 	if err != nil {
 		return err
 	}
@@ -135,13 +284,13 @@ func (c *Client) Do(method, path string, params url.Values, out interface{}) err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "go-onetimesecret/0.1")
+	req.Header.Set("User-Agent", c.userAgent())
 
 	if reqCB != nil {
 		reqCB(req)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return err
 	}
@@ -6,15 +6,21 @@
 //		onetimesecret [command]
 //
 //		Available Commands:
+//		burn        destroy a secret before it is read
 //		create      create a secret
 //		help        Help about any command
 //		inspect     View metadata about a secret
 //
 //		Flags:
-//			--apitoken string   API token for onetimesecret
-//			--cfg string        configuration file
-//			--username string   Username for onetimesecret
-//		-v, --verbose           More verbose output
+//			--apitoken string     API token for onetimesecret
+//			--base-url string     Base API URL, for use against a self-hosted onetimesecret instance
+//			--ca-cert string      CA certificate to verify the server with
+//			--cfg string          configuration file
+//			--client-cert string  Client certificate for mTLS authentication against a self-hosted instance
+//			--client-key string   Private key matching --client-cert
+//			--timeout duration    Per-request timeout (e.g. 10s). Zero means no timeout
+//			--username string     Username for onetimesecret
+//		-v, --verbose             More verbose output
 //
 //		Use "onetimesecret [command] --help" for more information about a command.
 //
@@ -22,6 +28,11 @@
 //
 //      username: <username>
 //      apitoken: <apitoken>
+//      base-url: <base-url>
+//      timeout: <timeout>
+//      client-cert: <client-cert>
+//      client-key: <client-key>
+//      ca-cert: <ca-cert>
 //
 // To get an API token simply signup at https://onetimesecret.com/
 package main
@@ -49,9 +60,15 @@ func main() {
 	cmd.PersistentFlags().BoolP("verbose", "v", false, "More verbose output")
 	cmd.PersistentFlags().String("username", "", "Username for onetimesecret")
 	cmd.PersistentFlags().String("apitoken", "", "API token for onetimesecret")
+	cmd.PersistentFlags().String("base-url", "", "Base API URL, for use against a self-hosted onetimesecret instance")
+	cmd.PersistentFlags().Duration("timeout", 0, "Per-request timeout (e.g. 10s). Zero means no timeout")
+	cmd.PersistentFlags().String("client-cert", "", "Client certificate for mTLS authentication against a self-hosted instance")
+	cmd.PersistentFlags().String("client-key", "", "Private key matching --client-cert")
+	cmd.PersistentFlags().String("ca-cert", "", "CA certificate to verify the server with")
 	cmd.AddCommand(
 		handleCreate(),
 		handleInspect(),
+		handleBurn(),
 	)
 
 	cobra.OnInitialize(func() {
@@ -65,6 +82,11 @@ func main() {
 
 		viper.BindEnv("username", "OTS_USERNAME")
 		viper.BindEnv("apitoken", "OTS_APITOKEN")
+		viper.BindEnv("base-url", "OTS_BASE_URL")
+		viper.BindEnv("timeout", "OTS_TIMEOUT")
+		viper.BindEnv("client-cert", "OTS_CLIENT_CERT")
+		viper.BindEnv("client-key", "OTS_CLIENT_KEY")
+		viper.BindEnv("ca-cert", "OTS_CA_CERT")
 
 		viper.AutomaticEnv()
 		err := viper.ReadInConfig()
@@ -81,10 +103,15 @@ func main() {
 
 }
 
-func getClient(cmd *cobra.Command) onetimesecret.Client {
+func getClient(cmd *cobra.Command) (onetimesecret.Client, error) {
 	f := cmd.Flags()
 	username, _ := f.GetString("username")
 	apitoken, _ := f.GetString("apitoken")
+	baseURL, _ := f.GetString("base-url")
+	timeout, _ := f.GetDuration("timeout")
+	clientCert, _ := f.GetString("client-cert")
+	clientKey, _ := f.GetString("client-key")
+	caCert, _ := f.GetString("ca-cert")
 
 	if username == "" {
 		username = viper.GetString("username")
@@ -92,12 +119,40 @@ func getClient(cmd *cobra.Command) onetimesecret.Client {
 	if apitoken == "" {
 		apitoken = viper.GetString("apitoken")
 	}
-	client := onetimesecret.Client{}
+	if baseURL == "" {
+		baseURL = viper.GetString("base-url")
+	}
+	if timeout == 0 {
+		timeout = viper.GetDuration("timeout")
+	}
+	if clientCert == "" {
+		clientCert = viper.GetString("client-cert")
+	}
+	if clientKey == "" {
+		clientKey = viper.GetString("client-key")
+	}
+	if caCert == "" {
+		caCert = viper.GetString("ca-cert")
+	}
+
+	client := onetimesecret.Client{
+		BaseURL:        baseURL,
+		RequestTimeout: timeout,
+	}
 	if apitoken != "" && username != "" {
 		client.APIToken = apitoken
 		client.Username = username
 	}
-	return client
+
+	if clientCert != "" && clientKey != "" {
+		tlsConfig, err := onetimesecret.LoadClientTLSConfig(clientCert, clientKey, caCert)
+		if err != nil {
+			return onetimesecret.Client{}, fmt.Errorf("could not set up mTLS: %v", err)
+		}
+		client.TLSConfig = tlsConfig
+	}
+
+	return client, nil
 }
 
 func handleCreate() *cobra.Command {
@@ -114,7 +169,10 @@ func handleCreate() *cobra.Command {
 				err error
 			)
 
-			c := getClient(cmd)
+			c, err := getClient(cmd)
+			if err != nil {
+				return err
+			}
 
 			opts := []onetimesecret.Option{}
 			if email != "" {
@@ -139,7 +197,7 @@ func handleCreate() *cobra.Command {
 			if email != "" {
 				fmt.Printf("Email with link has been sent to %v\n", m.Recipient)
 			} else {
-				fmt.Println("Secret path: ", "https://onetimesecret.com/secret/"+m.SecretKey)
+				fmt.Println("Secret path: ", c.ShareURL(m.SecretKey))
 			}
 			fmt.Println("Metadata key (do not share):", m.MetadataKey)
 
@@ -159,7 +217,10 @@ func handleInspect() *cobra.Command {
 		Use:   "inspect",
 		Short: "View metadata about a secret",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c := getClient(cmd)
+			c, err := getClient(cmd)
+			if err != nil {
+				return err
+			}
 			for i := range args {
 				m, err := c.RetrieveMetadata(args[i])
 				if err != nil {
@@ -178,7 +239,7 @@ func handleInspect() *cobra.Command {
 					fmt.Println("Sent to     :", m.Recipient[0])
 				}
 				if m.SecretKey != "" {
-					fmt.Println("Secret URL  :", "https://onetimesecret.com/secret/"+m.SecretKey)
+					fmt.Println("Secret URL  :", c.ShareURL(m.SecretKey))
 				}
 
 			}
@@ -187,3 +248,26 @@ func handleInspect() *cobra.Command {
 	}
 	return cmd
 }
+
+func handleBurn() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn <metadata-key>...",
+		Short: "destroy a secret before it is read",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := getClient(cmd)
+			if err != nil {
+				return err
+			}
+			for i := range args {
+				m, err := c.BurnSecret(args[i])
+				if err != nil {
+					return fmt.Errorf("could not burn secret: %v", err)
+				}
+				fmt.Println("Burned      :", m.MetadataKey)
+				fmt.Println("Status      :", m.Status())
+			}
+			return nil
+		},
+	}
+	return cmd
+}
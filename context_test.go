@@ -0,0 +1,87 @@
+package onetimesecret
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// stallingServer never writes a response until stop is closed, letting us
+// exercise context cancellation/deadlines against a request that would
+// otherwise hang.
+func stallingServer(t *testing.T) (srv *httptest.Server, stop func()) {
+	t.Helper()
+	done := make(chan struct{})
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+		w.Write([]byte(`{"custid": "anon"}`))
+	}))
+	return srv, func() {
+		close(done)
+		srv.Close()
+	}
+}
+
+func TestDoContextCancellation(t *testing.T) {
+	srv, stop := stallingServer(t)
+	defer stop()
+
+	c := Client{BaseURL: srv.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := c.DoContext(ctx, "POST", "", url.Values{}, &Metadata{})
+	if time.Since(start) > time.Second {
+		t.Fatalf("expected DoContext to return promptly after cancellation, took %v", time.Since(start))
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected an error wrapping %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestDoContextDeadlineExceeded(t *testing.T) {
+	srv, stop := stallingServer(t)
+	defer stop()
+
+	c := Client{BaseURL: srv.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.DoContext(ctx, "POST", "", url.Values{}, &Metadata{})
+	if time.Since(start) > time.Second {
+		t.Fatalf("expected DoContext to return promptly after the deadline, took %v", time.Since(start))
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected an error wrapping %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRequestTimeoutAppliesWithoutCallerDeadline(t *testing.T) {
+	srv, stop := stallingServer(t)
+	defer stop()
+
+	c := Client{BaseURL: srv.URL, RequestTimeout: 10 * time.Millisecond}
+
+	start := time.Now()
+	err := c.DoContext(context.Background(), "POST", "", url.Values{}, &Metadata{})
+	if time.Since(start) > time.Second {
+		t.Fatalf("expected RequestTimeout to cut off the request promptly, took %v", time.Since(start))
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected an error wrapping %v, got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,290 @@
+package onetimesecret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// saltedPrefix is the OpenSSL "Salted__" magic header prepended to ciphertext
+// produced by "openssl enc -salt", which is the framing onetimesecret.com's
+// browser-side encryption is compatible with.
+var saltedPrefix = []byte("Salted__")
+
+const (
+	saltLength = 8
+	keyLength  = 32 // AES-256
+	ivLength   = 16
+)
+
+// KeyDerivationFunc derives the AES key and IV used for client-side
+// encryption from a password and salt. It defaults to PBKDF2-HMAC-SHA512
+// with 300000 iterations, matching onetimesecret.com's own browser-side
+// encryption. Callers targeting a self-hosted instance with different
+// crypto settings can replace this.
+var KeyDerivationFunc = func(password, salt []byte) (key, iv []byte) {
+	derived := pbkdf2.Key(password, salt, 300000, keyLength+ivLength, sha512.New)
+	return derived[:keyLength], derived[keyLength:]
+}
+
+// PasswordLength is the number of characters in the randomly generated
+// passwords used by CreateEncryptedSecret and CreateEncryptedFile. It can be
+// adjusted by callers with different security requirements.
+var PasswordLength = 20
+
+// passwordAlphabet excludes characters that are easily confused with one
+// another (0/O, 1/l/I) so generated passwords are easy to read back from a
+// URL fragment.
+const passwordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+func randomPassword(n int) (string, error) {
+	alphabetLen := big.NewInt(int64(len(passwordAlphabet)))
+	buf := make([]byte, n)
+	for i := range buf {
+		idx, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = passwordAlphabet[idx.Int64()]
+	}
+	return string(buf), nil
+}
+
+// encrypt encrypts plaintext with an AES-256-CBC key+IV derived from
+// password and returns the base64-encoded OpenSSL "Salted__" framing.
+func encrypt(plaintext []byte, password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, iv := KeyDerivationFunc([]byte(password), salt)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	padded := pkcs7Pad(plaintext, block.BlockSize())
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	out := append(append([]byte{}, saltedPrefix...), salt...)
+	out = append(out, ciphertext...)
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// decrypt reverses encrypt, given the base64-encoded OpenSSL framing and the
+// password it was encrypted with.
+func decrypt(encoded string, password string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < len(saltedPrefix)+saltLength || !bytes.Equal(raw[:len(saltedPrefix)], saltedPrefix) {
+		return nil, errors.New("onetimesecret: not a Salted__ encoded payload")
+	}
+
+	salt := raw[len(saltedPrefix) : len(saltedPrefix)+saltLength]
+	ciphertext := raw[len(saltedPrefix)+saltLength:]
+
+	key, iv := KeyDerivationFunc([]byte(password), salt)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("onetimesecret: ciphertext is not a multiple of the block size")
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	return pkcs7Unpad(plaintext)
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padLen := blockSize - len(data)%blockSize
+	padding := bytes.Repeat([]byte{byte(padLen)}, padLen)
+	return append(data, padding...)
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("onetimesecret: cannot unpad empty data")
+	}
+	padLen := int(data[len(data)-1])
+	if padLen == 0 || padLen > len(data) {
+		return nil, errors.New("onetimesecret: invalid padding")
+	}
+	for _, b := range data[len(data)-padLen:] {
+		if int(b) != padLen {
+			return nil, errors.New("onetimesecret: invalid padding")
+		}
+	}
+	return data[:len(data)-padLen], nil
+}
+
+// EncryptedSecret is the result of creating a secret that was encrypted
+// client-side before being sent to onetimesecret.com. The server only ever
+// sees the ciphertext; the password lives solely in ShareURL's fragment.
+type EncryptedSecret struct {
+	Metadata
+
+	// Password is the randomly generated password the secret was
+	// encrypted with.
+	Password string
+
+	// ShareURL is the full link to give the recipient, of the form
+	// https://onetimesecret.com/secret/<key>#<password>. The password
+	// after the "#" is never sent to the server.
+	ShareURL string
+}
+
+// CreateEncryptedSecret encrypts value locally with a freshly generated
+// password before sending it to onetimesecret.com, so the server never sees
+// the plaintext. The password is embedded in the fragment of ShareURL and is
+// required, along with the secret key, to retrieve the value again with
+// RetrieveEncryptedSecret.
+func (c *Client) CreateEncryptedSecret(value string, opts ...Option) (EncryptedSecret, error) {
+	password, err := randomPassword(PasswordLength)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	ciphertext, err := encrypt([]byte(value), password)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	m, err := c.CreateSecret(ciphertext, opts...)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	return EncryptedSecret{
+		Metadata: m,
+		Password: password,
+		ShareURL: c.shareURL(m.SecretKey) + "#" + password,
+	}, nil
+}
+
+// RetrieveEncryptedSecret fetches the ciphertext for secretKey and decrypts
+// it with password, as generated by CreateEncryptedSecret.
+func (c *Client) RetrieveEncryptedSecret(secretKey, password string) (string, error) {
+	ciphertext, err := c.RetrieveSecret(secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := decrypt(ciphertext, password)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// File is a small binary blob shared through CreateEncryptedFile and
+// recovered with RetrieveEncryptedFile.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// fileEnvelope is the JSON structure encrypted as the plaintext by
+// CreateEncryptedFile.
+type fileEnvelope struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"`
+}
+
+// CreateEncryptedFile encrypts the contents of r, along with name and its
+// content type, as a small binary blob shared one time through
+// onetimesecret.com. As with CreateEncryptedSecret, the server never sees
+// the plaintext.
+func (c *Client) CreateEncryptedFile(name string, r io.Reader, opts ...Option) (EncryptedSecret, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	env := fileEnvelope{
+		Name:        name,
+		ContentType: http.DetectContentType(data),
+		Data:        base64.StdEncoding.EncodeToString(data),
+	}
+
+	plaintext, err := json.Marshal(env)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	password, err := randomPassword(PasswordLength)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	ciphertext, err := encrypt(plaintext, password)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	m, err := c.CreateSecret(ciphertext, opts...)
+	if err != nil {
+		return EncryptedSecret{}, err
+	}
+
+	return EncryptedSecret{
+		Metadata: m,
+		Password: password,
+		ShareURL: c.shareURL(m.SecretKey) + "#" + password,
+	}, nil
+}
+
+// RetrieveEncryptedFile fetches and decrypts a file shared with
+// CreateEncryptedFile.
+func (c *Client) RetrieveEncryptedFile(secretKey, password string) (File, error) {
+	ciphertext, err := c.RetrieveSecret(secretKey)
+	if err != nil {
+		return File{}, err
+	}
+
+	plaintext, err := decrypt(ciphertext, password)
+	if err != nil {
+		return File{}, err
+	}
+
+	env := fileEnvelope{}
+	if err := json.Unmarshal(plaintext, &env); err != nil {
+		return File{}, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(env.Data)
+	if err != nil {
+		return File{}, err
+	}
+
+	return File{
+		Name:        env.Name,
+		ContentType: env.ContentType,
+		Data:        data,
+	}, nil
+}
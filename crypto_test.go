@@ -0,0 +1,166 @@
+package onetimesecret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	ciphertext, err := encrypt([]byte("hunter2"), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	plaintext, err := decrypt(ciphertext, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("could not decrypt: %v", err)
+	}
+
+	if string(plaintext) != "hunter2" {
+		t.Errorf("expected %q but got %q", "hunter2", plaintext)
+	}
+}
+
+func TestEncryptSaltedFraming(t *testing.T) {
+	ciphertext, err := encrypt([]byte("hunter2"), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		t.Fatalf("ciphertext is not valid base64: %v", err)
+	}
+
+	if !bytes.HasPrefix(raw, saltedPrefix) {
+		t.Errorf("expected ciphertext to start with %q, got %q", saltedPrefix, raw[:len(saltedPrefix)])
+	}
+
+	if len(raw) < len(saltedPrefix)+saltLength {
+		t.Fatalf("ciphertext too short to contain a salt: %d bytes", len(raw))
+	}
+}
+
+func TestDecryptWrongPassword(t *testing.T) {
+	ciphertext, err := encrypt([]byte("hunter2"), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("could not encrypt: %v", err)
+	}
+
+	if _, err := decrypt(ciphertext, "wrong password"); err == nil {
+		t.Error("expected decrypting with the wrong password to fail")
+	}
+}
+
+func TestKeyDerivationFunc(t *testing.T) {
+	salt := []byte("12345678")
+	key, iv := KeyDerivationFunc([]byte("hunter2"), salt)
+
+	if len(key) != keyLength {
+		t.Errorf("expected a %d byte key, got %d", keyLength, len(key))
+	}
+	if len(iv) != ivLength {
+		t.Errorf("expected a %d byte IV, got %d", ivLength, len(iv))
+	}
+
+	key2, iv2 := KeyDerivationFunc([]byte("hunter2"), salt)
+	if !bytes.Equal(key, key2) || !bytes.Equal(iv, iv2) {
+		t.Error("expected deriving the key twice with the same password and salt to be deterministic")
+	}
+}
+
+func TestRandomPassword(t *testing.T) {
+	password, err := randomPassword(PasswordLength)
+	if err != nil {
+		t.Fatalf("could not generate password: %v", err)
+	}
+
+	if len(password) != PasswordLength {
+		t.Errorf("expected a %d character password, got %d (%q)", PasswordLength, len(password), password)
+	}
+
+	for _, r := range password {
+		if !strings.ContainsRune(passwordAlphabet, r) {
+			t.Errorf("password %q contains character %q outside of the safe alphabet", password, r)
+		}
+	}
+}
+
+// secretStoreServer is a minimal stand-in for the onetimesecret.com API,
+// just enough to exercise CreateEncryptedSecret/RetrieveEncryptedSecret and
+// CreateEncryptedFile/RetrieveEncryptedFile end to end.
+func secretStoreServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	secrets := map[string]string{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("could not parse form: %v", err)
+		}
+
+		switch {
+		case r.URL.Path == "/share":
+			secrets["mykey"] = r.FormValue("secret")
+			w.Write([]byte(`{"secret_key": "mykey", "metadata_key": "mymeta"}`))
+		case strings.HasPrefix(r.URL.Path, "/secret/"):
+			key := strings.TrimPrefix(r.URL.Path, "/secret/")
+			w.Write([]byte(`{"value": "` + secrets[key] + `"}`))
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestCreateAndRetrieveEncryptedSecret(t *testing.T) {
+	srv := secretStoreServer(t)
+	defer srv.Close()
+
+	c := Client{BaseURL: srv.URL}
+
+	enc, err := c.CreateEncryptedSecret("hunter2")
+	if err != nil {
+		t.Fatalf("could not create encrypted secret: %v", err)
+	}
+
+	wantURL := srv.URL + "/secret/mykey#" + enc.Password
+	if enc.ShareURL != wantURL {
+		t.Errorf("expected ShareURL %q, got %q", wantURL, enc.ShareURL)
+	}
+
+	value, err := c.RetrieveEncryptedSecret(enc.SecretKey, enc.Password)
+	if err != nil {
+		t.Fatalf("could not retrieve encrypted secret: %v", err)
+	}
+
+	if value != "hunter2" {
+		t.Errorf("expected %q but got %q", "hunter2", value)
+	}
+}
+
+func TestCreateAndRetrieveEncryptedFile(t *testing.T) {
+	srv := secretStoreServer(t)
+	defer srv.Close()
+
+	c := Client{BaseURL: srv.URL}
+
+	enc, err := c.CreateEncryptedFile("notes.txt", strings.NewReader("remember the milk"))
+	if err != nil {
+		t.Fatalf("could not create encrypted file: %v", err)
+	}
+
+	file, err := c.RetrieveEncryptedFile(enc.SecretKey, enc.Password)
+	if err != nil {
+		t.Fatalf("could not retrieve encrypted file: %v", err)
+	}
+
+	if file.Name != "notes.txt" {
+		t.Errorf("expected name %q but got %q", "notes.txt", file.Name)
+	}
+	if string(file.Data) != "remember the milk" {
+		t.Errorf("expected data %q but got %q", "remember the milk", file.Data)
+	}
+}
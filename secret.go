@@ -0,0 +1,75 @@
+package onetimesecret
+
+import "time"
+
+// SecretHandle is a small lifecycle manager around a secret's metadata,
+// returned by CreateSecretHandle and GenerateSecretHandle. It lets callers
+// keep working with a secret after creating it, without having to thread a
+// *Client and a metadata key through their own code.
+type SecretHandle struct {
+	client *Client
+	meta   Metadata
+}
+
+// CreateSecretHandle is CreateSecret, but wraps the resulting metadata in a
+// SecretHandle so the secret can be managed afterwards.
+func (c *Client) CreateSecretHandle(value string, opts ...Option) (*SecretHandle, error) {
+	m, err := c.CreateSecret(value, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretHandle{client: c, meta: m}, nil
+}
+
+// GenerateSecretHandle is GenerateSecret, but wraps the resulting metadata
+// in a SecretHandle so the secret can be managed afterwards.
+func (c *Client) GenerateSecretHandle(opts ...Option) (*SecretHandle, error) {
+	s, err := c.GenerateSecret(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretHandle{client: c, meta: s.Metadata}, nil
+}
+
+// Metadata returns the most recently known metadata for this secret. Call
+// Refresh first to update it.
+func (h *SecretHandle) Metadata() Metadata {
+	return h.meta
+}
+
+// Burn destroys the secret before it has been read and updates the handle
+// with the resulting metadata.
+func (h *SecretHandle) Burn() (Metadata, error) {
+	m, err := h.client.BurnSecret(h.meta.MetadataKey)
+	if err != nil {
+		return Metadata{}, err
+	}
+	h.meta = m
+	return m, nil
+}
+
+// Refresh re-fetches the metadata for this secret, updating the handle and
+// returning the new value.
+func (h *SecretHandle) Refresh() (Metadata, error) {
+	m, err := h.client.RetrieveMetadata(h.meta.MetadataKey)
+	if err != nil {
+		return Metadata{}, err
+	}
+	h.meta = m
+	return m, nil
+}
+
+// Status return "unread" or "read", as per Metadata.Status.
+func (h *SecretHandle) Status() string {
+	return h.meta.Status()
+}
+
+// Expired reports whether the secret's deadline has passed.
+func (h *SecretHandle) Expired() bool {
+	return time.Now().After(h.meta.Deadline())
+}
+
+// URL returns the link to give the recipient to read the secret.
+func (h *SecretHandle) URL() string {
+	return h.client.shareURL(h.meta.SecretKey)
+}
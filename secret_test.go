@@ -0,0 +1,103 @@
+package onetimesecret
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// burnServer is a minimal stand-in for the onetimesecret.com API, just
+// enough to exercise BurnSecret/RetrieveMetadata and the SecretHandle
+// lifecycle helpers built on top of them.
+func burnServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	meta := &Metadata{MetadataKey: "mymeta", SecretKey: "mykey"}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/private/mymeta/burn":
+			if meta.Received != 0 {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(APIError{Message: "Unknown secret"})
+				return
+			}
+			meta.Received = 1
+			json.NewEncoder(w).Encode(meta)
+		case r.URL.Path == "/private/mymeta":
+			json.NewEncoder(w).Encode(meta)
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestBurnSecret(t *testing.T) {
+	srv := burnServer(t)
+	defer srv.Close()
+
+	c := Client{BaseURL: srv.URL}
+
+	m, err := c.BurnSecret("mymeta")
+	if err != nil {
+		t.Fatalf("could not burn secret: %v", err)
+	}
+	if m.Status() != "read" {
+		t.Errorf("expected a burned secret to report status %q, got %q", "read", m.Status())
+	}
+}
+
+func TestBurnAlreadyReadSecret(t *testing.T) {
+	srv := burnServer(t)
+	defer srv.Close()
+
+	c := Client{BaseURL: srv.URL}
+
+	if _, err := c.BurnSecret("mymeta"); err != nil {
+		t.Fatalf("could not burn secret: %v", err)
+	}
+
+	_, err := c.BurnSecret("mymeta")
+	if err == nil {
+		t.Fatal("expected burning an already-read secret to fail")
+	}
+	if aerr, ok := err.(APIError); !ok || aerr.Message != "Unknown secret" {
+		t.Errorf("expected an APIError %q, got %T %v", "Unknown secret", err, err)
+	}
+}
+
+func TestSecretHandleBurnAndRefresh(t *testing.T) {
+	srv := burnServer(t)
+	defer srv.Close()
+
+	c := Client{BaseURL: srv.URL}
+	h := &SecretHandle{client: &c, meta: Metadata{MetadataKey: "mymeta", SecretKey: "mykey"}}
+
+	if h.Status() != "unread" {
+		t.Errorf("expected a fresh handle to report status %q, got %q", "unread", h.Status())
+	}
+
+	if _, err := h.Burn(); err != nil {
+		t.Fatalf("could not burn secret: %v", err)
+	}
+	if h.Status() != "read" {
+		t.Errorf("expected Burn to update the handle's status to %q, got %q", "read", h.Status())
+	}
+
+	if _, err := h.Refresh(); err != nil {
+		t.Fatalf("could not refresh metadata: %v", err)
+	}
+	if h.Status() != "read" {
+		t.Errorf("expected Refresh to report status %q, got %q", "read", h.Status())
+	}
+}
+
+func TestSecretHandleURLUsesBaseURL(t *testing.T) {
+	c := Client{BaseURL: "https://ots.example.com/api/v1"}
+	h := &SecretHandle{client: &c, meta: Metadata{SecretKey: "mykey"}}
+
+	want := "https://ots.example.com/secret/mykey"
+	if got := h.URL(); got != want {
+		t.Errorf("expected URL %q, got %q", want, got)
+	}
+}
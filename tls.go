@@ -0,0 +1,38 @@
+package onetimesecret
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// LoadClientTLSConfig builds a *tls.Config suitable for Client.TLSConfig from
+// a client certificate/key pair and, optionally, a CA certificate to verify
+// the server with. This is the mTLS setup needed to talk to a self-hosted
+// OTS instance sitting behind an mTLS-terminating reverse proxy. caFile may
+// be empty to use the system's root CAs.
+func LoadClientTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load client certificate: %v", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA certificate: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("could not parse CA certificate %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,104 @@
+package onetimesecret
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// selfSignedCert generates an in-memory self-signed certificate and key,
+// PEM encoded, for use as both the server and the client identity in tests.
+func selfSignedCert(t *testing.T) (certPEM, keyPEM []byte, cert tls.Certificate) {
+	t.Helper()
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+		DNSNames:     []string{"127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("could not load key pair: %v", err)
+	}
+
+	return certPEM, keyPEM, cert
+}
+
+func TestClientCertAuth(t *testing.T) {
+	certPEM, _, cert := selfSignedCert(t)
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			t.Error("expected server to receive a client certificate")
+		}
+		w.Write([]byte(`{"custid": "anon"}`))
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	clientTLSConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}
+
+	c := Client{
+		BaseURL:   srv.URL,
+		TLSConfig: clientTLSConfig,
+	}
+
+	m := Metadata{}
+	if err := c.Do("POST", "", url.Values{}, &m); err != nil {
+		t.Fatalf("could not perform request: %v", err)
+	}
+
+	if m.CustomerID != "anon" {
+		t.Errorf("expected anon customer id, got %q", m.CustomerID)
+	}
+}
+
+func TestHTTPClientReusedAcrossCalls(t *testing.T) {
+	c := Client{TLSConfig: &tls.Config{}}
+
+	first := c.httpClient()
+	second := c.httpClient()
+
+	if first != second {
+		t.Error("expected repeated calls to reuse the same *http.Client built from TLSConfig")
+	}
+}